@@ -0,0 +1,47 @@
+// Package featureconfig defines the global, process-wide feature flag state
+// that validator/client reads via Get() to toggle optional slashing
+// protection behavior. Only the flags validator/client actually consults are
+// defined here; the full flag set used by the rest of Prysm lives outside
+// this diff.
+package featureconfig
+
+import "sync"
+
+// Flags is the set of feature toggles read by validator/client.
+type Flags struct {
+	// SlasherProtection routes attestations through an external slasher
+	// service in addition to local slashing protection.
+	SlasherProtection bool
+	// SlashingProtectionMinimal selects the minimal slashing protection
+	// mode (lowest signed source/target epoch bounds) over the default
+	// full attestation history scan.
+	SlashingProtectionMinimal bool
+	// DoppelGangerProtectionOptOut disables the startup doppelganger
+	// observation window, letting a validator sign immediately. Intended
+	// for operators who intentionally hot-swap validator processes.
+	DoppelGangerProtectionOptOut bool
+}
+
+var (
+	featureConfigLock sync.RWMutex
+	featureConfig     *Flags
+)
+
+// Get returns the current process-wide feature flag configuration. Before
+// Init has been called, every flag reads as false.
+func Get() *Flags {
+	featureConfigLock.RLock()
+	defer featureConfigLock.RUnlock()
+	if featureConfig == nil {
+		return &Flags{}
+	}
+	return featureConfig
+}
+
+// Init sets the global feature flag configuration. It is called once at
+// startup after parsing CLI flags.
+func Init(c *Flags) {
+	featureConfigLock.Lock()
+	defer featureConfigLock.Unlock()
+	featureConfig = c
+}
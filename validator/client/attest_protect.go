@@ -16,27 +16,43 @@ var failedPreAttSignLocalErr = "attempted to make slashable attestation, rejecte
 var failedPreAttSignExternalErr = "attempted to make slashable attestation, rejected by external slasher service"
 var failedPostAttSignExternalErr = "external slasher service detected a submitted slashable attestation"
 
+// preAttSignValidations is a thin wrapper around preAttSignValidationsBatch
+// for the common single-attestation case.
 func (v *validator) preAttSignValidations(ctx context.Context, indexedAtt *ethpb.IndexedAttestation, pubKey [48]byte) error {
-	fmtKey := fmt.Sprintf("%#x", pubKey[:])
+	errs := v.preAttSignValidationsBatch(ctx, []AttSignRequest{{PubKey: pubKey, IndexedAtt: indexedAtt}})
+	return errs[0]
+}
 
-	v.attesterHistoryByPubKeyLock.RLock()
-	attesterHistory, ok := v.attesterHistoryByPubKey[pubKey]
-	v.attesterHistoryByPubKeyLock.RUnlock()
-	if ok && v.isNewAttSlashable(ctx, attesterHistory, indexedAtt.Data.Source.Epoch, indexedAtt.Data.Target.Epoch, indexedAtt) {
-		if v.emitAccountMetrics {
-			ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
-		}
-		return errors.New(failedPreAttSignLocalErr)
-	} else if !ok {
-		log.WithField("publicKey", fmtKey).Debug("Could not get local slashing protection data for validator")
+// preAttSignValidationForPubKey runs the local slashing protection check for
+// a single request. In non-minimal mode, the caller must already hold
+// attesterHistoryByPubKeyLock for read.
+func (v *validator) preAttSignValidationForPubKey(ctx context.Context, req AttSignRequest) error {
+	fmtKey := fmt.Sprintf("%#x", req.PubKey[:])
+	indexedAtt := req.IndexedAtt
+
+	if !v.doppelgangerReady(req.PubKey) {
+		return errors.New(failedPreAttSignDoppelgangerErr)
 	}
 
-	if featureconfig.Get().SlasherProtection && v.protector != nil {
-		if !v.protector.CheckAttestationSafety(ctx, indexedAtt) {
+	if featureconfig.Get().SlashingProtectionMinimal {
+		slashable, err := v.isNewAttSlashableMinimal(ctx, req.PubKey, indexedAtt.Data.Source.Epoch, indexedAtt.Data.Target.Epoch)
+		if err != nil {
+			log.WithError(err).Error("Could not check minimal slashing protection data for validator")
+		} else if slashable {
 			if v.emitAccountMetrics {
-				ValidatorAttestFailVecSlasher.WithLabelValues(fmtKey).Inc()
+				ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
 			}
-			return errors.New(failedPreAttSignExternalErr)
+			return errors.New(failedPreAttSignLocalErr)
+		}
+	} else {
+		attesterHistory, ok := v.attesterHistoryByPubKey[req.PubKey]
+		if ok && v.isNewAttSlashable(ctx, attesterHistory, indexedAtt.Data.Source.Epoch, indexedAtt.Data.Target.Epoch, indexedAtt) {
+			if v.emitAccountMetrics {
+				ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
+			}
+			return errors.New(failedPreAttSignLocalErr)
+		} else if !ok {
+			log.WithField("publicKey", fmtKey).Debug("Could not get local slashing protection data for validator")
 		}
 	}
 	return nil
@@ -44,15 +60,21 @@ func (v *validator) preAttSignValidations(ctx context.Context, indexedAtt *ethpb
 
 func (v *validator) postAttSignUpdate(ctx context.Context, indexedAtt *ethpb.IndexedAttestation, pubKey [48]byte) error {
 	fmtKey := fmt.Sprintf("%#x", pubKey[:])
-	v.attesterHistoryByPubKeyLock.Lock()
-	attesterHistory, ok := v.attesterHistoryByPubKey[pubKey]
-	if ok {
-		attesterHistory = markAttestationForTargetEpoch(ctx, attesterHistory, indexedAtt.Data.Source.Epoch, indexedAtt.Data.Target.Epoch)
-		v.attesterHistoryByPubKey[pubKey] = attesterHistory
+	if featureconfig.Get().SlashingProtectionMinimal {
+		if err := v.markAttestationForTargetEpochMinimal(ctx, pubKey, indexedAtt.Data.Source.Epoch, indexedAtt.Data.Target.Epoch); err != nil {
+			log.WithError(err).Error("Could not save minimal slashing protection data for validator")
+		}
 	} else {
-		log.WithField("publicKey", fmtKey).Debug("Could not get local slashing protection data for validator")
+		v.attesterHistoryByPubKeyLock.Lock()
+		attesterHistory, ok := v.attesterHistoryByPubKey[pubKey]
+		if ok {
+			attesterHistory = markAttestationForTargetEpoch(ctx, attesterHistory, indexedAtt.Data.Source.Epoch, indexedAtt.Data.Target.Epoch)
+			v.attesterHistoryByPubKey[pubKey] = attesterHistory
+		} else {
+			log.WithField("publicKey", fmtKey).Debug("Could not get local slashing protection data for validator")
+		}
+		v.attesterHistoryByPubKeyLock.Unlock()
 	}
-	v.attesterHistoryByPubKeyLock.Unlock()
 
 	if featureconfig.Get().SlasherProtection && v.protector != nil {
 		if !v.protector.CommitAttestation(ctx, indexedAtt) {
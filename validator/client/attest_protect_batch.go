@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+)
+
+// attSignValidationWorkerPoolSize bounds the number of surround/double-vote
+// checks run concurrently within a single preAttSignValidationsBatch call.
+const attSignValidationWorkerPoolSize = 16
+
+// AttSignRequest bundles an attestation with the public key that is about to
+// sign it, so that a whole slot's worth of duties can be validated together.
+type AttSignRequest struct {
+	PubKey     [48]byte
+	IndexedAtt *ethpb.IndexedAttestation
+}
+
+// preAttSignValidationsBatch validates N attestations for N different public
+// keys in a single call. The attesterHistoryByPubKeyLock is acquired once for
+// the whole batch rather than once per key, the local surround/double-vote
+// checks run in parallel over a bounded worker pool, and, when external
+// slasher protection is enabled, a single batched RPC replaces what would
+// otherwise be one round-trip per key. It returns one error per request, in
+// the same order as reqs; a nil entry means the request passed validation.
+func (v *validator) preAttSignValidationsBatch(ctx context.Context, reqs []AttSignRequest) []error {
+	errs := make([]error, len(reqs))
+
+	if !featureconfig.Get().SlashingProtectionMinimal {
+		v.attesterHistoryByPubKeyLock.RLock()
+	}
+	runBoundedWorkerPool(len(reqs), attSignValidationWorkerPoolSize, func(i int) {
+		errs[i] = v.preAttSignValidationForPubKey(ctx, reqs[i])
+	})
+	if !featureconfig.Get().SlashingProtectionMinimal {
+		v.attesterHistoryByPubKeyLock.RUnlock()
+	}
+
+	if featureconfig.Get().SlasherProtection && v.protector != nil {
+		v.checkAttestationSafetyBatch(ctx, reqs, errs)
+	}
+	return errs
+}
+
+// checkAttestationSafetyBatch sends every request that passed the local
+// check to the external slasher in a single round-trip, rather than one RPC
+// per public key, and fills in errs for any the slasher rejects.
+func (v *validator) checkAttestationSafetyBatch(ctx context.Context, reqs []AttSignRequest, errs []error) {
+	batch, ok := v.protector.(batchProtector)
+	if !ok {
+		// The configured protector does not support batched safety checks;
+		// fall back to one RPC per key.
+		for i, req := range reqs {
+			if errs[i] != nil {
+				continue
+			}
+			if !v.protector.CheckAttestationSafety(ctx, req.IndexedAtt) {
+				errs[i] = v.recordSlasherRejection(req.PubKey)
+			}
+		}
+		return
+	}
+
+	var indices []int
+	var atts []*ethpb.IndexedAttestation
+	for i, req := range reqs {
+		if errs[i] != nil {
+			continue
+		}
+		indices = append(indices, i)
+		atts = append(atts, req.IndexedAtt)
+	}
+	if len(atts) == 0 {
+		return
+	}
+	safe := batch.CheckAttestationSafetyBatch(ctx, atts)
+	for j, i := range indices {
+		if !safe[j] {
+			errs[i] = v.recordSlasherRejection(reqs[i].PubKey)
+		}
+	}
+}
+
+func (v *validator) recordSlasherRejection(pubKey [48]byte) error {
+	if v.emitAccountMetrics {
+		ValidatorAttestFailVecSlasher.WithLabelValues(fmt.Sprintf("%#x", pubKey[:])).Inc()
+	}
+	return errors.New(failedPreAttSignExternalErr)
+}
+
+// batchProtector is implemented by external slasher clients that support
+// validating many attestations in a single RPC.
+type batchProtector interface {
+	CheckAttestationSafetyBatch(ctx context.Context, atts []*ethpb.IndexedAttestation) []bool
+}
+
+// runBoundedWorkerPool calls fn(i) for every i in [0, n) using up to
+// maxWorkers goroutines at a time, and blocks until every call has returned.
+func runBoundedWorkerPool(n, maxWorkers int, fn func(i int)) {
+	if n == 0 {
+		return
+	}
+	if maxWorkers > n {
+		maxWorkers = n
+	}
+	sem := make(chan struct{}, maxWorkers)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// isNewAttSlashableMinimal implements the "minimal" slashing protection rule:
+// an attestation is slashable unless its source and target epochs are both
+// strictly greater than the highest source and target epoch previously
+// signed by this public key (the bounds read through
+// LowestSignedSourceEpoch and LowestSignedTargetEpoch). This is
+// strictly stronger than the spec's surround/double-vote rule (it also
+// rejects equal-source non-double votes), but only requires two integers per
+// validator instead of the full weak-subjectivity-period history, so it
+// avoids the O(wsPeriod) scans done in isNewAttSlashable.
+//
+// v.db is an iface.ValidatorDB, backed by either validator/db/kv (BoltDB) or
+// validator/db/sql (SQLite); both implement LowestSignedSourceEpoch and
+// LowestSignedTargetEpoch.
+func (v *validator) isNewAttSlashableMinimal(ctx context.Context, pubKey [48]byte, sourceEpoch, targetEpoch uint64) (bool, error) {
+	lowestSource, exists, err := v.db.LowestSignedSourceEpoch(ctx, pubKey)
+	if err != nil {
+		return false, errors.Wrap(err, "could not get lowest signed source epoch")
+	}
+	if exists && sourceEpoch <= lowestSource {
+		return true, nil
+	}
+	lowestTarget, exists, err := v.db.LowestSignedTargetEpoch(ctx, pubKey)
+	if err != nil {
+		return false, errors.Wrap(err, "could not get lowest signed target epoch")
+	}
+	if exists && targetEpoch <= lowestTarget {
+		return true, nil
+	}
+	return false, nil
+}
+
+// markAttestationForTargetEpochMinimal raises the lowest signed source and
+// target epoch bounds for pubKey to sourceEpoch/targetEpoch. Bounds are
+// monotonic: a lower epoch than what is already stored is never written.
+func (v *validator) markAttestationForTargetEpochMinimal(ctx context.Context, pubKey [48]byte, sourceEpoch, targetEpoch uint64) error {
+	if err := v.db.SaveLowestSignedSourceEpoch(ctx, pubKey, sourceEpoch); err != nil {
+		return errors.Wrap(err, "could not save lowest signed source epoch")
+	}
+	if err := v.db.SaveLowestSignedTargetEpoch(ctx, pubKey, targetEpoch); err != nil {
+		return errors.Wrap(err, "could not save lowest signed target epoch")
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package client
+
+import (
+	"context"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// preAttSignValidationsForSlot runs local (and, if configured, external)
+// slashing protection validation for every public key attesting in a single
+// slot, via one preAttSignValidationsBatch call instead of one
+// preAttSignValidations call per key. Callers with only one attestation to
+// validate should use preAttSignValidations instead. It returns one error
+// per entry in indexedAtts, keyed by position, matching the order of
+// pubKeys; the two slices must be the same length.
+func (v *validator) preAttSignValidationsForSlot(ctx context.Context, indexedAtts []*ethpb.IndexedAttestation, pubKeys [][48]byte) []error {
+	reqs := make([]AttSignRequest, len(indexedAtts))
+	for i, indexedAtt := range indexedAtts {
+		reqs[i] = AttSignRequest{PubKey: pubKeys[i], IndexedAtt: indexedAtt}
+	}
+	return v.preAttSignValidationsBatch(ctx, reqs)
+}
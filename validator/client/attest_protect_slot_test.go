@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// TestPreAttSignValidationsForSlot_MultipleKeys verifies that validating a
+// whole slot's worth of duties for several public keys in one call returns
+// one result per key, exercising preAttSignValidationsBatch with more than
+// one request.
+func TestPreAttSignValidationsForSlot_MultipleKeys(t *testing.T) {
+	ctx := context.Background()
+	v := &validator{}
+
+	pubKeys := make([][48]byte, 3)
+	indexedAtts := make([]*ethpb.IndexedAttestation, 3)
+	for i := range pubKeys {
+		copy(pubKeys[i][:], []byte{byte(i)})
+		indexedAtts[i] = &ethpb.IndexedAttestation{
+			Data: &ethpb.AttestationData{
+				Source: &ethpb.Checkpoint{Epoch: 1},
+				Target: &ethpb.Checkpoint{Epoch: 2},
+			},
+		}
+	}
+
+	errs := v.preAttSignValidationsForSlot(ctx, indexedAtts, pubKeys)
+	if len(errs) != len(pubKeys) {
+		t.Fatalf("got %d results, want %d", len(errs), len(pubKeys))
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("key %d: unexpected error with no existing attestation history: %v", i, err)
+		}
+	}
+}
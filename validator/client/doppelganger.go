@@ -0,0 +1,109 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prysmaticlabs/prysm/shared/promauto"
+)
+
+// doppelgangerObservationEpochs is how long a newly started validator client
+// watches the beacon chain for attestations from its own public keys before
+// it will sign anything. Two epochs is enough to see an attestation land for
+// every slot in the validator's duty schedule.
+const doppelgangerObservationEpochs = 2
+
+// doppelgangerStatus is the outcome of the doppelganger observation window
+// for a single public key.
+type doppelgangerStatus int
+
+const (
+	// doppelgangerObserving means the observation window has not yet elapsed.
+	doppelgangerObserving doppelgangerStatus = iota
+	// doppelgangerClear means no attestation was seen from this key during
+	// the observation window; it is safe to sign.
+	doppelgangerClear
+	// doppelgangerDetected means an attestation from this key was seen
+	// on-chain during the observation window, meaning another instance is
+	// already live with the same key. This key will never become ready.
+	doppelgangerDetected
+)
+
+// ValidatorDoppelgangerStatus exposes the per-key doppelganger status so that
+// operators can alert on a stuck "observing" or a "detected" key.
+var ValidatorDoppelgangerStatus = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "validator_doppelganger_status",
+		Help: "Doppelganger detection status per public key: 0 observing, 1 clear, 2 detected",
+	},
+	[]string{"pubkey"},
+)
+
+// doppelgangerService tracks, for every public key loaded at startup, whether
+// it is safe to sign attestations yet. This type only owns the state machine
+// and the ready-gate that preAttSignValidationForPubKey consults; the
+// validator run loop is responsible for constructing it with
+// newDoppelgangerService at startup and driving it with ObserveAttestation
+// and AdvanceEpoch as it watches the beacon chain. That run-loop wiring has
+// not landed in this tree yet, so doppelgangerService exists but nothing
+// constructs or feeds one.
+type doppelgangerService struct {
+	lock       sync.RWMutex
+	startEpoch uint64
+	statuses   map[[48]byte]doppelgangerStatus
+}
+
+// newDoppelgangerService begins an observation window starting at startEpoch
+// for every key in pubKeys.
+func newDoppelgangerService(startEpoch uint64, pubKeys [][48]byte) *doppelgangerService {
+	statuses := make(map[[48]byte]doppelgangerStatus, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		statuses[pubKey] = doppelgangerObserving
+		ValidatorDoppelgangerStatus.WithLabelValues(fmt.Sprintf("%#x", pubKey[:])).Set(float64(doppelgangerObserving))
+	}
+	return &doppelgangerService{startEpoch: startEpoch, statuses: statuses}
+}
+
+// ObserveAttestation is called whenever an attestation from pubKey is
+// observed included on chain. If that key is still within its observation
+// window, this means another running instance already holds it, so the key
+// is permanently marked detected and will never become ready.
+func (d *doppelgangerService) ObserveAttestation(pubKey [48]byte) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if status, ok := d.statuses[pubKey]; !ok || status != doppelgangerObserving {
+		return
+	}
+	d.statuses[pubKey] = doppelgangerDetected
+	ValidatorDoppelgangerStatus.WithLabelValues(fmt.Sprintf("%#x", pubKey[:])).Set(float64(doppelgangerDetected))
+}
+
+// AdvanceEpoch is called once per new head epoch. Once the observation window
+// has elapsed, every key that has not been detected is marked clear.
+func (d *doppelgangerService) AdvanceEpoch(epoch uint64) {
+	if epoch < d.startEpoch+doppelgangerObservationEpochs {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for pubKey, status := range d.statuses {
+		if status == doppelgangerObserving {
+			d.statuses[pubKey] = doppelgangerClear
+			ValidatorDoppelgangerStatus.WithLabelValues(fmt.Sprintf("%#x", pubKey[:])).Set(float64(doppelgangerClear))
+		}
+	}
+}
+
+// status returns the current doppelganger status for pubKey. A key that was
+// never registered (loaded after the service started) is treated as clear,
+// since it was never at risk of having been observed.
+func (d *doppelgangerService) status(pubKey [48]byte) doppelgangerStatus {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	status, ok := d.statuses[pubKey]
+	if !ok {
+		return doppelgangerClear
+	}
+	return status
+}
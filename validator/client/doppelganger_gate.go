@@ -0,0 +1,36 @@
+package client
+
+import (
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+)
+
+var failedPreAttSignDoppelgangerErr = "attempted to make an attestation before doppelganger protection ascertained another instance isn't already live with this key"
+var failedProposeDoppelgangerErr = "attempted to propose a block before doppelganger protection ascertained another instance isn't already live with this key"
+
+// doppelgangerReady reports whether pubKey has cleared its startup
+// doppelganger observation window and may sign attestations. Operators who
+// intentionally hot-swap validator processes (e.g. during a migration) can
+// opt out by setting featureconfig's DoppelGangerProtectionOptOut flag.
+//
+// v.doppelgangerService is populated by the validator run loop at startup and
+// fed attestations observed on chain as they arrive; that wiring has not
+// landed in this tree yet, so v.doppelgangerService is currently always nil
+// and this gate is a no-op until it does.
+func (v *validator) doppelgangerReady(pubKey [48]byte) bool {
+	if featureconfig.Get().DoppelGangerProtectionOptOut {
+		return true
+	}
+	if v.doppelgangerService == nil {
+		return true
+	}
+	return v.doppelgangerService.status(pubKey) == doppelgangerClear
+}
+
+// doppelgangerReadyForProposal is the proposer analogue of doppelgangerReady:
+// it reports whether pubKey has cleared its startup doppelganger observation
+// window and may sign a block proposal. It shares the same gate state as
+// attestation signing, since a doppelganger observed via either duty implies
+// another instance is already live with this key.
+func (v *validator) doppelgangerReadyForProposal(pubKey [48]byte) bool {
+	return v.doppelgangerReady(pubKey)
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+
+	pb "github.com/prysmaticlabs/prysm/validator/pb"
+)
+
+// DoppelgangerStatus implements the validator gRPC service method of the
+// same name, reporting whether each requested public key has cleared its
+// startup doppelganger observation window.
+func (v *validator) DoppelgangerStatus(ctx context.Context, req *pb.DoppelgangerStatusRequest) (*pb.DoppelgangerStatusResponse, error) {
+	resp := &pb.DoppelgangerStatusResponse{}
+	for _, rawPubKey := range req.PublicKeys {
+		var pubKey [48]byte
+		copy(pubKey[:], rawPubKey)
+		resp.Statuses = append(resp.Statuses, &pb.DoppelgangerStatusResponse_Status{
+			PublicKey: rawPubKey,
+			Ready:     v.doppelgangerReady(pubKey),
+		})
+	}
+	return resp, nil
+}
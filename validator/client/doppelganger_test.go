@@ -0,0 +1,43 @@
+package client
+
+import "testing"
+
+func TestDoppelgangerService_StatusTransitions(t *testing.T) {
+	var observed, clear [48]byte
+	copy(observed[:], []byte("observed"))
+	copy(clear[:], []byte("clear"))
+
+	d := newDoppelgangerService(10, [][48]byte{observed, clear})
+
+	if got := d.status(observed); got != doppelgangerObserving {
+		t.Fatalf("new key status = %v, want doppelgangerObserving", got)
+	}
+
+	d.ObserveAttestation(observed)
+	if got := d.status(observed); got != doppelgangerDetected {
+		t.Fatalf("after ObserveAttestation status = %v, want doppelgangerDetected", got)
+	}
+
+	// Still within the observation window: the unobserved key stays observing.
+	d.AdvanceEpoch(11)
+	if got := d.status(clear); got != doppelgangerObserving {
+		t.Fatalf("before window elapses status = %v, want doppelgangerObserving", got)
+	}
+
+	// Window has elapsed: the unobserved key clears, the detected key stays detected.
+	d.AdvanceEpoch(10 + doppelgangerObservationEpochs)
+	if got := d.status(clear); got != doppelgangerClear {
+		t.Fatalf("after window elapses status = %v, want doppelgangerClear", got)
+	}
+	if got := d.status(observed); got != doppelgangerDetected {
+		t.Fatalf("detected key status = %v, want it to stay doppelgangerDetected", got)
+	}
+
+	// A key never registered with the service (e.g. loaded after startup)
+	// was never at risk of being observed, so it reads as clear.
+	var unregistered [48]byte
+	copy(unregistered[:], []byte("unregistered"))
+	if got := d.status(unregistered); got != doppelgangerClear {
+		t.Fatalf("unregistered key status = %v, want doppelgangerClear", got)
+	}
+}
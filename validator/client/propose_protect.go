@@ -0,0 +1,19 @@
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// preBlockSignValidation runs the doppelganger protection check a block
+// proposal must pass before pubKey is allowed to sign it. It is the proposer
+// analogue of preAttSignValidationForPubKey: the block proposal pipeline is
+// not present in this tree, so this is the entry point such a pipeline would
+// call.
+func (v *validator) preBlockSignValidation(ctx context.Context, pubKey [48]byte) error {
+	if !v.doppelgangerReadyForProposal(pubKey) {
+		return errors.New(failedProposeDoppelgangerErr)
+	}
+	return nil
+}
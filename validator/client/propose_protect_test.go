@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"testing"
+)
+
+// TestPreBlockSignValidation_GatesOnDoppelgangerStatus verifies that a block
+// proposal is rejected while a key's doppelganger status is still detected,
+// and allowed once it clears.
+func TestPreBlockSignValidation_GatesOnDoppelgangerStatus(t *testing.T) {
+	ctx := context.Background()
+
+	var detected, cleared [48]byte
+	copy(detected[:], []byte("detected"))
+	copy(cleared[:], []byte("cleared"))
+
+	d := newDoppelgangerService(0, [][48]byte{detected, cleared})
+	d.ObserveAttestation(detected)
+	d.AdvanceEpoch(doppelgangerObservationEpochs)
+
+	v := &validator{doppelgangerService: d}
+
+	if err := v.preBlockSignValidation(ctx, detected); err == nil {
+		t.Fatal("expected proposal to be rejected for a key with a detected doppelganger")
+	}
+	if err := v.preBlockSignValidation(ctx, cleared); err != nil {
+		t.Fatalf("expected proposal to be allowed for a cleared key, got: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+// ExportSlashingProtectionFlag is the path passed to
+// `validator slashing-protection export --file`.
+var ExportSlashingProtectionFlag = &cli.StringFlag{
+	Name:  "file",
+	Usage: "Path to write the EIP-3076 slashing protection interchange file to",
+}
+
+// ImportSlashingProtectionFlag is the path passed to
+// `validator slashing-protection import --file`.
+var ImportSlashingProtectionFlag = &cli.StringFlag{
+	Name:  "file",
+	Usage: "Path to an EIP-3076 slashing protection interchange file to import",
+}
+
+// ExportSlashingProtectionCLI implements `validator slashing-protection export`.
+func ExportSlashingProtectionCLI(cliCtx *cli.Context, v *validator) error {
+	ctx := context.Background()
+	minimal := cliCtx.Bool("minimal")
+	interchange, err := v.ExportSlashingProtectionJSON(ctx, minimal)
+	if err != nil {
+		return errors.Wrap(err, "could not export slashing protection history")
+	}
+	filePath := cliCtx.String(ExportSlashingProtectionFlag.Name)
+	if filePath == "" {
+		return errors.New("--file must be set to a destination path")
+	}
+	if err := SaveSlashingProtectionJSON(filePath, interchange); err != nil {
+		return errors.Wrap(err, "could not write interchange file")
+	}
+	log.WithField("file", filePath).Info("Exported slashing protection history")
+	return nil
+}
+
+// ImportSlashingProtectionCLI implements `validator slashing-protection import`.
+func ImportSlashingProtectionCLI(cliCtx *cli.Context, v *validator) error {
+	ctx := context.Background()
+	filePath := cliCtx.String(ImportSlashingProtectionFlag.Name)
+	if filePath == "" {
+		return errors.New("--file must be set to a source path")
+	}
+	interchange, err := LoadSlashingProtectionJSON(filePath)
+	if err != nil {
+		return errors.Wrap(err, "could not read interchange file")
+	}
+	if err := v.ImportSlashingProtectionJSON(ctx, interchange); err != nil {
+		return errors.Wrap(err, "could not import slashing protection history")
+	}
+	log.WithField("file", filePath).Info("Imported slashing protection history")
+	return nil
+}
@@ -0,0 +1,292 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/validator/db/kv"
+)
+
+// interchangeFormatVersion is the version of the EIP-3076 slashing protection
+// interchange format that this client reads and writes.
+const interchangeFormatVersion = "3"
+
+// EIPSlashingProtectionFormat is the top-level schema for the EIP-3076
+// interchange format, used to migrate slashing protection history between
+// client implementations (Prysm, Lighthouse, Nimbus, Teku) without risking a
+// double-sign.
+type EIPSlashingProtectionFormat struct {
+	Metadata *InterchangeMetadata   `json:"metadata"`
+	Data     []*ProtectionData      `json:"data"`
+}
+
+// InterchangeMetadata identifies the format version and the chain the
+// history was recorded against.
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+}
+
+// ProtectionData is the per-validator slashing protection history. In the
+// "minimal" variant, SignedBlocks and SignedAttestations each contain at
+// most a single entry recording the maximum slot/source/target ever signed,
+// and none if nothing of that kind was signed.
+type ProtectionData struct {
+	Pubkey             string               `json:"pubkey"`
+	SignedBlocks       []*SignedBlock       `json:"signed_blocks"`
+	SignedAttestations []*SignedAttestation `json:"signed_attestations"`
+}
+
+// SignedBlock is a single proposal entry in the interchange format.
+type SignedBlock struct {
+	Slot        string `json:"slot"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// SignedAttestation is a single attestation entry in the interchange format.
+type SignedAttestation struct {
+	SourceEpoch string `json:"source_epoch"`
+	TargetEpoch string `json:"target_epoch"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+var errGenesisValidatorsRootMismatch = errors.New(
+	"genesis validators root of import does not match this node's, refusing to import",
+)
+
+// ExportSlashingProtectionJSON reads the in-memory attesting history for
+// every loaded public key, together with that key's proposal history from
+// the slashing protection database, and serializes it into the EIP-3076
+// interchange format. When minimal is true, only the highest source/target
+// epoch and slot ever signed are exported for each validator.
+func (v *validator) ExportSlashingProtectionJSON(ctx context.Context, minimal bool) (*EIPSlashingProtectionFormat, error) {
+	v.attesterHistoryByPubKeyLock.RLock()
+	defer v.attesterHistoryByPubKeyLock.RUnlock()
+
+	interchange := &EIPSlashingProtectionFormat{
+		Metadata: &InterchangeMetadata{
+			InterchangeFormatVersion: interchangeFormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", v.genesisValidatorsRoot[:]),
+		},
+	}
+	for pubKey, history := range v.attesterHistoryByPubKey {
+		data, err := exportHistoryForPubKey(ctx, pubKey, history, minimal)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not export history for public key %#x", pubKey)
+		}
+		blocks, err := v.exportSignedBlocksForPubKey(ctx, pubKey, minimal)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not export proposal history for public key %#x", pubKey)
+		}
+		data.SignedBlocks = blocks
+		interchange.Data = append(interchange.Data, data)
+	}
+	return interchange, nil
+}
+
+// exportSignedBlocksForPubKey reads the proposal history recorded for pubKey
+// in the slashing protection database and formats it as interchange
+// SignedBlock entries. When minimal is true, only the highest slot ever
+// signed is emitted, mirroring how exportHistoryForPubKey collapses
+// attestations down to the highest source/target.
+func (v *validator) exportSignedBlocksForPubKey(ctx context.Context, pubKey [48]byte, minimal bool) ([]*SignedBlock, error) {
+	slotToSigningRoot, err := v.db.SignedBlocksForPubKey(ctx, pubKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read signed blocks")
+	}
+	if minimal {
+		if len(slotToSigningRoot) == 0 {
+			return []*SignedBlock{}, nil
+		}
+		var highestSlot uint64
+		for slot := range slotToSigningRoot {
+			if slot > highestSlot {
+				highestSlot = slot
+			}
+		}
+		return []*SignedBlock{{Slot: strconv.FormatUint(highestSlot, 10)}}, nil
+	}
+	slots := make([]uint64, 0, len(slotToSigningRoot))
+	for slot := range slotToSigningRoot {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	blocks := make([]*SignedBlock, 0, len(slots))
+	for _, slot := range slots {
+		block := &SignedBlock{Slot: strconv.FormatUint(slot, 10)}
+		if signingRoot := slotToSigningRoot[slot]; len(signingRoot) > 0 {
+			block.SigningRoot = fmt.Sprintf("%#x", signingRoot)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// exportHistoryForPubKey walks the full encapsulated attester history and
+// either emits every attested target epoch (complete variant) or collapses
+// it down to the single highest source/target ever signed (minimal variant).
+func exportHistoryForPubKey(ctx context.Context, pubKey [48]byte, history *kv.EncHistoryData, minimal bool) (*ProtectionData, error) {
+	data := &ProtectionData{Pubkey: fmt.Sprintf("%#x", pubKey[:])}
+	if history == nil {
+		return data, nil
+	}
+	lew, err := history.GetLatestEpochWritten(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get latest epoch written")
+	}
+
+	var highestSource, highestTarget uint64
+	var everSigned bool
+	for target := uint64(0); target <= lew; target++ {
+		hd := safeTargetToSource(ctx, history, target)
+		if hd == (*kv.HistoryData)(nil) {
+			continue
+		}
+		if minimal {
+			everSigned = true
+			if hd.Source > highestSource {
+				highestSource = hd.Source
+			}
+			if target > highestTarget {
+				highestTarget = target
+			}
+			continue
+		}
+		data.SignedAttestations = append(data.SignedAttestations, &SignedAttestation{
+			SourceEpoch: strconv.FormatUint(hd.Source, 10),
+			TargetEpoch: strconv.FormatUint(target, 10),
+		})
+	}
+	if minimal && everSigned {
+		data.SignedAttestations = []*SignedAttestation{{
+			SourceEpoch: strconv.FormatUint(highestSource, 10),
+			TargetEpoch: strconv.FormatUint(highestTarget, 10),
+		}}
+	}
+	return data, nil
+}
+
+// ImportSlashingProtectionJSON merges an EIP-3076 interchange file into the
+// validator's existing slashing protection history. The import is
+// conservative: for every public key, the resulting bounds are the max of
+// whatever was already on disk and whatever is being imported, so an import
+// can only ever make future signing stricter, never looser.
+func (v *validator) ImportSlashingProtectionJSON(ctx context.Context, interchange *EIPSlashingProtectionFormat) error {
+	if interchange.Metadata == nil {
+		return errors.New("interchange file is missing its metadata section")
+	}
+	importedRoot, err := hex.DecodeString(strings.TrimPrefix(interchange.Metadata.GenesisValidatorsRoot, "0x"))
+	if err != nil {
+		return errors.Wrap(err, "genesis_validators_root is not valid hex")
+	}
+	if !bytes.Equal(importedRoot, v.genesisValidatorsRoot[:]) {
+		return errGenesisValidatorsRootMismatch
+	}
+
+	v.attesterHistoryByPubKeyLock.Lock()
+	defer v.attesterHistoryByPubKeyLock.Unlock()
+
+	for _, record := range interchange.Data {
+		pubKey, err := decodeHexPubKey(record.Pubkey)
+		if err != nil {
+			return errors.Wrapf(err, "could not decode public key %s", record.Pubkey)
+		}
+		history, ok := v.attesterHistoryByPubKey[pubKey]
+		if !ok {
+			history, err = kv.NewAttestationHistoryArray(0)
+			if err != nil {
+				return errors.Wrap(err, "could not initialize attestation history")
+			}
+		}
+		for _, att := range record.SignedAttestations {
+			source, err := strconv.ParseUint(att.SourceEpoch, 10, 64)
+			if err != nil {
+				return errors.Wrap(err, "could not parse source_epoch")
+			}
+			target, err := strconv.ParseUint(att.TargetEpoch, 10, 64)
+			if err != nil {
+				return errors.Wrap(err, "could not parse target_epoch")
+			}
+			history, err = mergeImportedAttestation(ctx, history, source, target)
+			if err != nil {
+				return errors.Wrapf(err, "could not merge imported attestation for public key %#x", pubKey)
+			}
+		}
+		v.attesterHistoryByPubKey[pubKey] = history
+
+		for _, block := range record.SignedBlocks {
+			slot, err := strconv.ParseUint(block.Slot, 10, 64)
+			if err != nil {
+				return errors.Wrap(err, "could not parse slot")
+			}
+			var signingRoot []byte
+			if block.SigningRoot != "" {
+				signingRoot, err = hex.DecodeString(strings.TrimPrefix(block.SigningRoot, "0x"))
+				if err != nil {
+					return errors.Wrap(err, "could not decode signing_root")
+				}
+			}
+			if err := v.db.SaveSignedBlock(ctx, pubKey, slot, signingRoot); err != nil {
+				return errors.Wrapf(err, "could not save imported signed block for public key %#x", pubKey)
+			}
+		}
+	}
+	return nil
+}
+
+// mergeImportedAttestation folds an imported (sourceEpoch, targetEpoch) entry
+// into history, keeping whichever source is higher for that target epoch so
+// that import can only ever make future signing stricter, never looser.
+func mergeImportedAttestation(ctx context.Context, history *kv.EncHistoryData, sourceEpoch, targetEpoch uint64) (*kv.EncHistoryData, error) {
+	if existing := safeTargetToSource(ctx, history, targetEpoch); existing != (*kv.HistoryData)(nil) && existing.Source >= sourceEpoch {
+		return history, nil
+	}
+	history = markAttestationForTargetEpoch(ctx, history, sourceEpoch, targetEpoch)
+	if history == nil {
+		return nil, errors.New("could not merge attestation into history")
+	}
+	return history, nil
+}
+
+func decodeHexPubKey(hexKey string) ([48]byte, error) {
+	var pubKey [48]byte
+	raw, err := hex.DecodeString(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return pubKey, err
+	}
+	if len(raw) != 48 {
+		return pubKey, fmt.Errorf("public key %s has length %d, expected 48", hexKey, len(raw))
+	}
+	copy(pubKey[:], raw)
+	return pubKey, nil
+}
+
+// SaveSlashingProtectionJSON writes an interchange file to disk at the given path.
+func SaveSlashingProtectionJSON(path string, interchange *EIPSlashingProtectionFormat) error {
+	enc, err := json.MarshalIndent(interchange, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal interchange data")
+	}
+	return ioutil.WriteFile(path, enc, 0600)
+}
+
+// LoadSlashingProtectionJSON reads and parses an interchange file from disk.
+func LoadSlashingProtectionJSON(path string) (*EIPSlashingProtectionFormat, error) {
+	enc, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read interchange file")
+	}
+	interchange := &EIPSlashingProtectionFormat{}
+	if err := json.Unmarshal(enc, interchange); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal interchange file")
+	}
+	return interchange, nil
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/validator/db/kv"
+)
+
+// TestImportSlashingProtectionJSON_MergeIsMax verifies that importing an
+// interchange file never lowers the source epoch already recorded for a
+// target epoch: the merged bound is always the max of what was on disk and
+// what is being imported.
+func TestImportSlashingProtectionJSON_MergeIsMax(t *testing.T) {
+	ctx := context.Background()
+
+	var pubKey [48]byte
+	copy(pubKey[:], []byte("pubkey"))
+	var genesisRoot [32]byte
+	copy(genesisRoot[:], []byte("genesis"))
+
+	history, err := kv.NewAttestationHistoryArray(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history = markAttestationForTargetEpoch(ctx, history, 50 /* source */, 100 /* target */)
+	if history == nil {
+		t.Fatal("could not seed existing attestation history")
+	}
+
+	v := &validator{
+		genesisValidatorsRoot: genesisRoot,
+		attesterHistoryByPubKey: map[[48]byte]*kv.EncHistoryData{
+			pubKey: history,
+		},
+	}
+
+	newInterchange := func(sourceEpoch string) *EIPSlashingProtectionFormat {
+		return &EIPSlashingProtectionFormat{
+			Metadata: &InterchangeMetadata{
+				InterchangeFormatVersion: interchangeFormatVersion,
+				GenesisValidatorsRoot:    fmt.Sprintf("%#x", genesisRoot[:]),
+			},
+			Data: []*ProtectionData{
+				{
+					Pubkey: fmt.Sprintf("%#x", pubKey[:]),
+					SignedAttestations: []*SignedAttestation{
+						{SourceEpoch: sourceEpoch, TargetEpoch: "100"},
+					},
+				},
+			},
+		}
+	}
+
+	// Importing a lower source for an already-recorded target must not
+	// weaken the existing bound.
+	if err := v.ImportSlashingProtectionJSON(ctx, newInterchange("10")); err != nil {
+		t.Fatal(err)
+	}
+	got := safeTargetToSource(ctx, v.attesterHistoryByPubKey[pubKey], 100)
+	if got == nil || got.Source != 50 {
+		t.Fatalf("import with a lower source weakened the existing bound: got %#v, want source 50", got)
+	}
+
+	// Importing a higher source for the same target must still raise the bound.
+	if err := v.ImportSlashingProtectionJSON(ctx, newInterchange("75")); err != nil {
+		t.Fatal(err)
+	}
+	got = safeTargetToSource(ctx, v.attesterHistoryByPubKey[pubKey], 100)
+	if got == nil || got.Source != 75 {
+		t.Fatalf("import with a higher source did not raise the bound: got %#v, want source 75", got)
+	}
+}
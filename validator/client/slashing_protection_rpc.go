@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	pb "github.com/prysmaticlabs/prysm/validator/pb"
+)
+
+// ExportSlashingProtection implements the validator gRPC service method of
+// the same name, returning the node's slashing protection history as a
+// JSON-encoded EIP-3076 interchange file.
+func (v *validator) ExportSlashingProtection(ctx context.Context, req *pb.ExportSlashingProtectionRequest) (*pb.ExportSlashingProtectionResponse, error) {
+	interchange, err := v.ExportSlashingProtectionJSON(ctx, req.Minimal)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not export slashing protection history")
+	}
+	enc, err := marshalInterchangeJSON(interchange)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal interchange data")
+	}
+	return &pb.ExportSlashingProtectionResponse{File: enc}, nil
+}
+
+// ImportSlashingProtection implements the validator gRPC service method of
+// the same name, merging a JSON-encoded EIP-3076 interchange file into the
+// node's existing slashing protection history.
+func (v *validator) ImportSlashingProtection(ctx context.Context, req *pb.ImportSlashingProtectionRequest) (*pb.ImportSlashingProtectionResponse, error) {
+	interchange, err := unmarshalInterchangeJSON(req.File)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal interchange data")
+	}
+	if err := v.ImportSlashingProtectionJSON(ctx, interchange); err != nil {
+		return nil, errors.Wrap(err, "could not import slashing protection history")
+	}
+	return &pb.ImportSlashingProtectionResponse{}, nil
+}
+
+func marshalInterchangeJSON(interchange *EIPSlashingProtectionFormat) ([]byte, error) {
+	return json.Marshal(interchange)
+}
+
+func unmarshalInterchangeJSON(raw []byte) (*EIPSlashingProtectionFormat, error) {
+	interchange := &EIPSlashingProtectionFormat{}
+	if err := json.Unmarshal(raw, interchange); err != nil {
+		return nil, err
+	}
+	return interchange, nil
+}
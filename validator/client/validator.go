@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/validator/db/iface"
+	"github.com/prysmaticlabs/prysm/validator/db/kv"
+)
+
+// Protector is implemented by an external slasher client that can validate
+// an attestation before it is signed and record it once it is.
+type Protector interface {
+	CheckAttestationSafety(ctx context.Context, indexedAtt *ethpb.IndexedAttestation) bool
+	CommitAttestation(ctx context.Context, indexedAtt *ethpb.IndexedAttestation) bool
+}
+
+// validator holds the slashing-protection-relevant state for a running
+// validator client instance. This is not the full validator type; it only
+// declares the fields the slashing protection and doppelganger protection
+// code in this package depends on.
+type validator struct {
+	genesisValidatorsRoot [32]byte
+
+	// db is the slashing protection storage backend selected by
+	// validator/db.NewDB; either the BoltDB-backed validator/db/kv store or
+	// the SQLite-backed validator/db/sql store.
+	db iface.ValidatorDB
+
+	attesterHistoryByPubKeyLock sync.RWMutex
+	attesterHistoryByPubKey     map[[48]byte]*kv.EncHistoryData
+
+	// doppelgangerService tracks the startup doppelganger observation
+	// window; nil until the run loop constructs one.
+	doppelgangerService *doppelgangerService
+
+	// protector is the external slasher client consulted when
+	// featureconfig.Get().SlasherProtection is enabled; nil otherwise.
+	protector Protector
+
+	emitAccountMetrics bool
+}
@@ -0,0 +1,45 @@
+// Package db selects a slashing protection storage backend for the
+// validator client. Both backends implement iface.ValidatorDB and share the
+// same EIP-3076 interchange import/export path in validator/client.
+package db
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/validator/db/iface"
+	"github.com/prysmaticlabs/prysm/validator/db/kv"
+	"github.com/prysmaticlabs/prysm/validator/db/sql"
+	"github.com/urfave/cli/v2"
+)
+
+// BackendFlag selects the slashing protection storage backend, e.g.
+// --slashing-protection-db-backend=sqlite. Defaults to the original BoltDB
+// store when unset.
+var BackendFlag = &cli.StringFlag{
+	Name:  "slashing-protection-db-backend",
+	Usage: "Slashing protection database backend to use: bolt (default) or sqlite",
+	Value: string(BackendBolt),
+}
+
+// Backend identifies which storage engine backs slashing protection.
+type Backend string
+
+const (
+	// BackendBolt is the original BoltDB key-value store.
+	BackendBolt Backend = "bolt"
+	// BackendSQLite is the relational SQLite store, selected with
+	// --slashing-protection-db-backend=sqlite.
+	BackendSQLite Backend = "sqlite"
+)
+
+// NewDB opens the slashing protection database at dirPath using the
+// requested backend.
+func NewDB(dirPath string, backend Backend) (iface.ValidatorDB, error) {
+	switch backend {
+	case "", BackendBolt:
+		return kv.NewKVStore(dirPath, nil)
+	case BackendSQLite:
+		return sql.NewStore(dirPath)
+	default:
+		return nil, errors.Errorf("unknown slashing protection db backend %q", backend)
+	}
+}
@@ -0,0 +1,50 @@
+package iface
+
+import (
+	"context"
+	"io"
+)
+
+// ValidatorDB is the interface every slashing protection storage backend
+// must satisfy. It is implemented by the BoltDB-backed validator/db/kv store
+// and the SQLite-backed validator/db/sql store, so that preAttSignValidations
+// and postAttSignUpdate can be written against a single abstraction
+// regardless of which backend an operator selected.
+type ValidatorDB interface {
+	io.Closer
+
+	// LowestSignedSourceEpoch returns the minimal slashing protection mode's
+	// source epoch bound for pubKey: the highest source epoch saved through
+	// SaveLowestSignedSourceEpoch. exists is false if none has been saved.
+	LowestSignedSourceEpoch(ctx context.Context, pubKey [48]byte) (epoch uint64, exists bool, err error)
+	// SaveLowestSignedSourceEpoch raises the lowest signed source epoch for
+	// pubKey to epoch. It is a no-op if epoch does not raise the bound.
+	SaveLowestSignedSourceEpoch(ctx context.Context, pubKey [48]byte, epoch uint64) error
+	// LowestSignedTargetEpoch returns the minimal slashing protection mode's
+	// target epoch bound for pubKey: the highest target epoch saved through
+	// SaveLowestSignedTargetEpoch. exists is false if none has been saved.
+	LowestSignedTargetEpoch(ctx context.Context, pubKey [48]byte) (epoch uint64, exists bool, err error)
+	// SaveLowestSignedTargetEpoch raises the lowest signed target epoch for
+	// pubKey to epoch. It is a no-op if epoch does not raise the bound.
+	SaveLowestSignedTargetEpoch(ctx context.Context, pubKey [48]byte, epoch uint64) error
+
+	// SignedAttestationsForPubKey returns every signed_attestations row
+	// recorded for pubKey. validator/client does not call it: interchange
+	// export reads the in-memory kv.EncHistoryData history, and non-minimal
+	// surround-vote validation scans that same in-memory history regardless
+	// of backend.
+	SignedAttestationsForPubKey(ctx context.Context, pubKey [48]byte) (sourceToTarget map[uint64]uint64, err error)
+	// SaveSignedAttestation records that pubKey signed an attestation with
+	// the given source and target epoch.
+	SaveSignedAttestation(ctx context.Context, pubKey [48]byte, sourceEpoch, targetEpoch uint64) error
+	// SurroundingSignedAttestation reports whether pubKey has already signed
+	// an attestation whose [source, target) range surrounds or is surrounded
+	// by [sourceEpoch, targetEpoch).
+	SurroundingSignedAttestation(ctx context.Context, pubKey [48]byte, sourceEpoch, targetEpoch uint64) (bool, error)
+
+	// SignedBlocksForPubKey returns every signed_blocks row recorded for
+	// pubKey, used when exporting the "complete" interchange variant.
+	SignedBlocksForPubKey(ctx context.Context, pubKey [48]byte) (slotToSigningRoot map[uint64][]byte, err error)
+	// SaveSignedBlock records that pubKey signed a block proposal at slot.
+	SaveSignedBlock(ctx context.Context, pubKey [48]byte, slot uint64, signingRoot []byte) error
+}
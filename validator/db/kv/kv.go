@@ -0,0 +1,32 @@
+// Package kv is the original BoltDB-backed slashing protection store for the
+// validator client. Store predates the iface.ValidatorDB abstraction; this
+// file only declares the type and constructor. The buckets and methods added
+// to satisfy iface.ValidatorDB live in slashing_protection_parity.go.
+package kv
+
+import (
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store is the BoltDB-backed slashing protection database for a single
+// validator client instance.
+type Store struct {
+	db *bolt.DB
+}
+
+// NewKVStore opens (creating if necessary) the BoltDB slashing protection
+// database under dirPath. pubKeys is not used by this constructor; it may
+// be nil.
+func NewKVStore(dirPath string, pubKeys [][48]byte) (*Store, error) {
+	db, err := bolt.Open(dirPath+"/validator.db", 0600, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open bolt db")
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying BoltDB database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
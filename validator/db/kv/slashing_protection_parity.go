@@ -0,0 +1,208 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// These buckets back the iface.ValidatorDB methods added for minimal
+// slashing protection mode and for the "complete" EIP-3076 interchange
+// variant, so that Store (this package's BoltDB-backed implementation) has
+// the same surface as validator/db/sql.Store and can be selected as the
+// default backend behind that interface.
+var (
+	lowestSignedSourceEpochBucket = []byte("lowest-signed-source-epoch")
+	lowestSignedTargetEpochBucket = []byte("lowest-signed-target-epoch")
+	signedAttestationsBucket      = []byte("signed-attestations")
+	signedBlocksBucket            = []byte("signed-blocks")
+)
+
+func uint64ToBytes(i uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, i)
+	return b
+}
+
+func bytesToUint64(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
+
+// LowestSignedSourceEpoch returns the source epoch bound for pubKey: the
+// highest epoch saved through SaveLowestSignedSourceEpoch.
+func (s *Store) LowestSignedSourceEpoch(ctx context.Context, pubKey [48]byte) (uint64, bool, error) {
+	var epoch uint64
+	var exists bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(lowestSignedSourceEpochBucket)
+		if bkt == nil {
+			return nil
+		}
+		v := bkt.Get(pubKey[:])
+		if v == nil {
+			return nil
+		}
+		exists = true
+		epoch = bytesToUint64(v)
+		return nil
+	})
+	return epoch, exists, errors.Wrap(err, "could not read lowest signed source epoch")
+}
+
+// SaveLowestSignedSourceEpoch raises the lowest signed source epoch for
+// pubKey to epoch. It is a no-op if epoch does not raise the bound.
+func (s *Store) SaveLowestSignedSourceEpoch(ctx context.Context, pubKey [48]byte, epoch uint64) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(lowestSignedSourceEpochBucket)
+		if err != nil {
+			return err
+		}
+		if existing := bkt.Get(pubKey[:]); existing != nil && bytesToUint64(existing) >= epoch {
+			return nil
+		}
+		return bkt.Put(pubKey[:], uint64ToBytes(epoch))
+	})
+	return errors.Wrap(err, "could not save lowest signed source epoch")
+}
+
+// LowestSignedTargetEpoch returns the target epoch bound for pubKey: the
+// highest epoch saved through SaveLowestSignedTargetEpoch.
+func (s *Store) LowestSignedTargetEpoch(ctx context.Context, pubKey [48]byte) (uint64, bool, error) {
+	var epoch uint64
+	var exists bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(lowestSignedTargetEpochBucket)
+		if bkt == nil {
+			return nil
+		}
+		v := bkt.Get(pubKey[:])
+		if v == nil {
+			return nil
+		}
+		exists = true
+		epoch = bytesToUint64(v)
+		return nil
+	})
+	return epoch, exists, errors.Wrap(err, "could not read lowest signed target epoch")
+}
+
+// SaveLowestSignedTargetEpoch raises the lowest signed target epoch for
+// pubKey to epoch. It is a no-op if epoch does not raise the bound.
+func (s *Store) SaveLowestSignedTargetEpoch(ctx context.Context, pubKey [48]byte, epoch uint64) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(lowestSignedTargetEpochBucket)
+		if err != nil {
+			return err
+		}
+		if existing := bkt.Get(pubKey[:]); existing != nil && bytesToUint64(existing) >= epoch {
+			return nil
+		}
+		return bkt.Put(pubKey[:], uint64ToBytes(epoch))
+	})
+	return errors.Wrap(err, "could not save lowest signed target epoch")
+}
+
+// SignedAttestationsForPubKey returns every signed attestation recorded for
+// pubKey as a map of target epoch to source epoch. Not yet called by
+// validator/client: non-minimal mode still checks slashability against the
+// in-memory kv.EncHistoryData bucket scan regardless of the selected backend.
+func (s *Store) SignedAttestationsForPubKey(ctx context.Context, pubKey [48]byte) (map[uint64]uint64, error) {
+	sourceToTarget := make(map[uint64]uint64)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(signedAttestationsBucket)
+		if bkt == nil {
+			return nil
+		}
+		validatorBkt := bkt.Bucket(pubKey[:])
+		if validatorBkt == nil {
+			return nil
+		}
+		return validatorBkt.ForEach(func(targetKey, sourceVal []byte) error {
+			sourceToTarget[bytesToUint64(targetKey)] = bytesToUint64(sourceVal)
+			return nil
+		})
+	})
+	return sourceToTarget, errors.Wrap(err, "could not read signed attestations")
+}
+
+// SaveSignedAttestation records that pubKey signed an attestation with the
+// given source and target epoch.
+func (s *Store) SaveSignedAttestation(ctx context.Context, pubKey [48]byte, sourceEpoch, targetEpoch uint64) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(signedAttestationsBucket)
+		if err != nil {
+			return err
+		}
+		validatorBkt, err := bkt.CreateBucketIfNotExists(pubKey[:])
+		if err != nil {
+			return err
+		}
+		return validatorBkt.Put(uint64ToBytes(targetEpoch), uint64ToBytes(sourceEpoch))
+	})
+	return errors.Wrap(err, "could not save signed attestation")
+}
+
+// SurroundingSignedAttestation reports whether pubKey has already signed an
+// attestation whose [source, target) range strictly surrounds or is
+// strictly surrounded by [sourceEpoch, targetEpoch). Equal bounds are not
+// slashable, so both comparisons are strict. Not yet called by
+// validator/client; see SignedAttestationsForPubKey.
+func (s *Store) SurroundingSignedAttestation(ctx context.Context, pubKey [48]byte, sourceEpoch, targetEpoch uint64) (bool, error) {
+	sourceToTarget, err := s.SignedAttestationsForPubKey(ctx, pubKey)
+	if err != nil {
+		return false, err
+	}
+	for target, source := range sourceToTarget {
+		if target == targetEpoch {
+			continue
+		}
+		if source > sourceEpoch && target < targetEpoch {
+			return true, nil
+		}
+		if source < sourceEpoch && target > targetEpoch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SignedBlocksForPubKey returns every signed block recorded for pubKey as a
+// map of slot to signing root.
+func (s *Store) SignedBlocksForPubKey(ctx context.Context, pubKey [48]byte) (map[uint64][]byte, error) {
+	slotToSigningRoot := make(map[uint64][]byte)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(signedBlocksBucket)
+		if bkt == nil {
+			return nil
+		}
+		validatorBkt := bkt.Bucket(pubKey[:])
+		if validatorBkt == nil {
+			return nil
+		}
+		return validatorBkt.ForEach(func(slotKey, signingRoot []byte) error {
+			root := make([]byte, len(signingRoot))
+			copy(root, signingRoot)
+			slotToSigningRoot[bytesToUint64(slotKey)] = root
+			return nil
+		})
+	})
+	return slotToSigningRoot, errors.Wrap(err, "could not read signed blocks")
+}
+
+// SaveSignedBlock records that pubKey signed a block proposal at slot.
+func (s *Store) SaveSignedBlock(ctx context.Context, pubKey [48]byte, slot uint64, signingRoot []byte) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(signedBlocksBucket)
+		if err != nil {
+			return err
+		}
+		validatorBkt, err := bkt.CreateBucketIfNotExists(pubKey[:])
+		if err != nil {
+			return err
+		}
+		return validatorBkt.Put(uint64ToBytes(slot), signingRoot)
+	})
+	return errors.Wrap(err, "could not save signed block")
+}
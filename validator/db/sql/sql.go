@@ -0,0 +1,285 @@
+// Package sql implements a SQLite-backed alternative to the BoltDB
+// slashing protection store in validator/db/kv. It trades BoltDB's
+// single-writer key-value bucket layout for a small relational schema,
+// modeled after Nimbus's slashing protection database.
+//
+// Store answers the minimal-mode bound checks (LowestSignedSourceEpoch,
+// LowestSignedTargetEpoch) used by validator/client's minimal slashing
+// protection mode. It also exposes a relational surround-vote query
+// (SurroundingSignedAttestation) and per-row attestation/block history,
+// intended to let non-minimal mode answer the surround-vote check with two
+// indexed range queries instead of a linear scan over an encapsulated
+// history blob; that non-minimal wiring has not landed yet, so selecting
+// this backend currently only benefits minimal mode.
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+
+	// sqlite3 registers itself as a database/sql driver named "sqlite3".
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+)
+
+const databaseFileName = "validator_slashing_protection.sql"
+
+const schema = `
+CREATE TABLE IF NOT EXISTS validators (
+	id     INTEGER PRIMARY KEY AUTOINCREMENT,
+	pubkey BLOB UNIQUE NOT NULL
+);
+CREATE TABLE IF NOT EXISTS signed_attestations (
+	validator_id INTEGER NOT NULL,
+	source_epoch INTEGER NOT NULL,
+	target_epoch INTEGER NOT NULL,
+	signing_root BLOB,
+	PRIMARY KEY (validator_id, target_epoch),
+	FOREIGN KEY (validator_id) REFERENCES validators(id)
+);
+CREATE INDEX IF NOT EXISTS idx_signed_attestations_source
+	ON signed_attestations(validator_id, source_epoch);
+CREATE TABLE IF NOT EXISTS signed_blocks (
+	validator_id INTEGER NOT NULL,
+	slot         INTEGER NOT NULL,
+	signing_root BLOB,
+	PRIMARY KEY (validator_id, slot),
+	FOREIGN KEY (validator_id) REFERENCES validators(id)
+);
+CREATE TABLE IF NOT EXISTS lowest_signed_epochs (
+	validator_id       INTEGER PRIMARY KEY,
+	lowest_source_epoch INTEGER,
+	lowest_target_epoch INTEGER,
+	FOREIGN KEY (validator_id) REFERENCES validators(id)
+);
+`
+
+// Store is a SQLite-backed implementation of iface.ValidatorDB.
+type Store struct {
+	db               *sql.DB
+	databaseFilePath string
+}
+
+// NewStore opens (creating if necessary) a SQLite slashing protection
+// database under dirPath.
+func NewStore(dirPath string) (*Store, error) {
+	fullPath := filepath.Join(dirPath, databaseFileName)
+	db, err := sql.Open("sqlite3", fullPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open sqlite database")
+	}
+	// SQLite allows only one writer at a time; serializing access to a
+	// single *sql.DB connection avoids "database is locked" errors under
+	// chunk0-4's concurrent worker-pool batch validation, and busy_timeout
+	// covers the remaining window where a write is still in flight.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`PRAGMA busy_timeout = 5000;`); err != nil {
+		return nil, errors.Wrap(err, "could not set sqlite busy timeout")
+	}
+	if _, err := db.Exec(schema); err != nil {
+		return nil, errors.Wrap(err, "could not initialize sqlite schema")
+	}
+	return &Store{db: db, databaseFilePath: fullPath}, nil
+}
+
+// Close closes the underlying database connection.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// validatorID looks up the row id for pubKey, inserting it first if this is
+// the first time it has been seen. INSERT OR IGNORE makes that insert safe
+// against two concurrent batched writers racing to register the same new
+// pubkey: whichever insert loses to the UNIQUE constraint is silently
+// dropped, and the following SELECT finds the row either way.
+func (s *Store) validatorID(ctx context.Context, pubKey [48]byte) (int64, error) {
+	if _, err := s.db.ExecContext(ctx, `INSERT OR IGNORE INTO validators(pubkey) VALUES (?)`, pubKey[:]); err != nil {
+		return 0, errors.Wrap(err, "could not insert validator")
+	}
+	var id int64
+	err := s.db.QueryRowContext(ctx, `SELECT id FROM validators WHERE pubkey = ?`, pubKey[:]).Scan(&id)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not query validator")
+	}
+	return id, nil
+}
+
+// LowestSignedSourceEpoch returns the source epoch bound for pubKey: the
+// highest epoch saved through SaveLowestSignedSourceEpoch.
+func (s *Store) LowestSignedSourceEpoch(ctx context.Context, pubKey [48]byte) (uint64, bool, error) {
+	id, err := s.validatorID(ctx, pubKey)
+	if err != nil {
+		return 0, false, err
+	}
+	var epoch sql.NullInt64
+	err = s.db.QueryRowContext(ctx, `SELECT lowest_source_epoch FROM lowest_signed_epochs WHERE validator_id = ?`, id).Scan(&epoch)
+	if err == sql.ErrNoRows || !epoch.Valid {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrap(err, "could not query lowest signed source epoch")
+	}
+	return uint64(epoch.Int64), true, nil
+}
+
+// SaveLowestSignedSourceEpoch raises the lowest signed source epoch for pubKey to epoch.
+func (s *Store) SaveLowestSignedSourceEpoch(ctx context.Context, pubKey [48]byte, epoch uint64) error {
+	id, err := s.validatorID(ctx, pubKey)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO lowest_signed_epochs(validator_id, lowest_source_epoch)
+		VALUES (?, ?)
+		ON CONFLICT(validator_id) DO UPDATE SET
+			lowest_source_epoch = MAX(COALESCE(lowest_source_epoch, excluded.lowest_source_epoch), excluded.lowest_source_epoch)
+	`, id, epoch)
+	return errors.Wrap(err, "could not save lowest signed source epoch")
+}
+
+// LowestSignedTargetEpoch returns the target epoch bound for pubKey: the
+// highest epoch saved through SaveLowestSignedTargetEpoch.
+func (s *Store) LowestSignedTargetEpoch(ctx context.Context, pubKey [48]byte) (uint64, bool, error) {
+	id, err := s.validatorID(ctx, pubKey)
+	if err != nil {
+		return 0, false, err
+	}
+	var epoch sql.NullInt64
+	err = s.db.QueryRowContext(ctx, `SELECT lowest_target_epoch FROM lowest_signed_epochs WHERE validator_id = ?`, id).Scan(&epoch)
+	if err == sql.ErrNoRows || !epoch.Valid {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrap(err, "could not query lowest signed target epoch")
+	}
+	return uint64(epoch.Int64), true, nil
+}
+
+// SaveLowestSignedTargetEpoch raises the lowest signed target epoch for pubKey to epoch.
+func (s *Store) SaveLowestSignedTargetEpoch(ctx context.Context, pubKey [48]byte, epoch uint64) error {
+	id, err := s.validatorID(ctx, pubKey)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO lowest_signed_epochs(validator_id, lowest_target_epoch)
+		VALUES (?, ?)
+		ON CONFLICT(validator_id) DO UPDATE SET
+			lowest_target_epoch = MAX(COALESCE(lowest_target_epoch, excluded.lowest_target_epoch), excluded.lowest_target_epoch)
+	`, id, epoch)
+	return errors.Wrap(err, "could not save lowest signed target epoch")
+}
+
+// SignedAttestationsForPubKey returns every signed_attestations row recorded
+// for pubKey. Not yet called by validator/client: non-minimal mode still
+// checks slashability against the in-memory kv.EncHistoryData bucket scan
+// regardless of the selected backend.
+func (s *Store) SignedAttestationsForPubKey(ctx context.Context, pubKey [48]byte) (map[uint64]uint64, error) {
+	id, err := s.validatorID(ctx, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT source_epoch, target_epoch FROM signed_attestations WHERE validator_id = ?`, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query signed attestations")
+	}
+	defer rows.Close()
+	sourceToTarget := make(map[uint64]uint64)
+	for rows.Next() {
+		var source, target uint64
+		if err := rows.Scan(&source, &target); err != nil {
+			return nil, errors.Wrap(err, "could not scan signed attestation row")
+		}
+		sourceToTarget[target] = source
+	}
+	return sourceToTarget, rows.Err()
+}
+
+// SaveSignedAttestation records that pubKey signed an attestation with the given source and target epoch.
+func (s *Store) SaveSignedAttestation(ctx context.Context, pubKey [48]byte, sourceEpoch, targetEpoch uint64) error {
+	id, err := s.validatorID(ctx, pubKey)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO signed_attestations(validator_id, source_epoch, target_epoch)
+		VALUES (?, ?, ?)
+		ON CONFLICT(validator_id, target_epoch) DO UPDATE SET source_epoch = excluded.source_epoch
+	`, id, sourceEpoch, targetEpoch)
+	return errors.Wrap(err, "could not save signed attestation")
+}
+
+// SurroundingSignedAttestation reports whether pubKey has already signed an
+// attestation whose [source, target) range strictly surrounds or is
+// strictly surrounded by [sourceEpoch, targetEpoch). Each direction is
+// answered by its own indexed range query against (validator_id,
+// source_epoch) and the target_epoch primary key, rather than a linear
+// scan. The comparisons are strict, matching the spec's
+// is_slashable_attestation_data surround condition: an equal source epoch
+// with a different target epoch is not slashable. Not yet called by
+// validator/client; see SignedAttestationsForPubKey.
+func (s *Store) SurroundingSignedAttestation(ctx context.Context, pubKey [48]byte, sourceEpoch, targetEpoch uint64) (bool, error) {
+	id, err := s.validatorID(ctx, pubKey)
+	if err != nil {
+		return false, err
+	}
+	// Surrounds an existing vote: existing source > new source and existing target < new target (not a duplicate).
+	var count int
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM signed_attestations
+		WHERE validator_id = ? AND source_epoch > ? AND target_epoch < ? AND target_epoch != ?
+	`, id, sourceEpoch, targetEpoch, targetEpoch).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "could not query surrounding attestations")
+	}
+	if count > 0 {
+		return true, nil
+	}
+	// Surrounded by an existing vote: existing source < new source and existing target > new target (not a duplicate).
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM signed_attestations
+		WHERE validator_id = ? AND source_epoch < ? AND target_epoch > ? AND target_epoch != ?
+	`, id, sourceEpoch, targetEpoch, targetEpoch).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "could not query surrounded attestations")
+	}
+	return count > 0, nil
+}
+
+// SignedBlocksForPubKey returns every signed_blocks row recorded for pubKey.
+func (s *Store) SignedBlocksForPubKey(ctx context.Context, pubKey [48]byte) (map[uint64][]byte, error) {
+	id, err := s.validatorID(ctx, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT slot, signing_root FROM signed_blocks WHERE validator_id = ?`, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query signed blocks")
+	}
+	defer rows.Close()
+	slotToSigningRoot := make(map[uint64][]byte)
+	for rows.Next() {
+		var slot uint64
+		var signingRoot []byte
+		if err := rows.Scan(&slot, &signingRoot); err != nil {
+			return nil, errors.Wrap(err, "could not scan signed block row")
+		}
+		slotToSigningRoot[slot] = signingRoot
+	}
+	return slotToSigningRoot, rows.Err()
+}
+
+// SaveSignedBlock records that pubKey signed a block proposal at slot.
+func (s *Store) SaveSignedBlock(ctx context.Context, pubKey [48]byte, slot uint64, signingRoot []byte) error {
+	id, err := s.validatorID(ctx, pubKey)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO signed_blocks(validator_id, slot, signing_root)
+		VALUES (?, ?, ?)
+		ON CONFLICT(validator_id, slot) DO UPDATE SET signing_root = excluded.signing_root
+	`, id, slot, signingRoot)
+	return errors.Wrap(err, "could not save signed block")
+}
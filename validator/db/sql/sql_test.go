@@ -0,0 +1,95 @@
+package sql
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// TestStore_LowestSignedEpoch_Monotonic signs two ascending epochs and then
+// attempts a third, lower one, asserting the stored bound only ever rises.
+func TestStore_LowestSignedEpoch_Monotonic(t *testing.T) {
+	tests := []struct {
+		name string
+		save func(s *Store, ctx context.Context, pubKey [48]byte, epoch uint64) error
+		get  func(s *Store, ctx context.Context, pubKey [48]byte) (uint64, bool, error)
+	}{
+		{"source", (*Store).SaveLowestSignedSourceEpoch, (*Store).LowestSignedSourceEpoch},
+		{"target", (*Store).SaveLowestSignedTargetEpoch, (*Store).LowestSignedTargetEpoch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir, err := ioutil.TempDir("", "sql-slashing-protection")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			store, err := NewStore(dir)
+			if err != nil {
+				t.Fatalf("could not create store: %v", err)
+			}
+			defer store.Close()
+
+			var pubKey [48]byte
+			copy(pubKey[:], []byte(tt.name))
+
+			epochs := []uint64{10, 20, 5}
+			want := []uint64{10, 20, 20}
+			for i, epoch := range epochs {
+				if err := tt.save(store, ctx, pubKey, epoch); err != nil {
+					t.Fatalf("save(%d): %v", epoch, err)
+				}
+				got, exists, err := tt.get(store, ctx, pubKey)
+				if err != nil {
+					t.Fatalf("get after save(%d): %v", epoch, err)
+				}
+				if !exists || got != want[i] {
+					t.Fatalf("after saving %d (round %d): got bound %d (exists=%v), want %d", epoch, i, got, exists, want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestStore_SurroundingSignedAttestation_EqualSourceNotSlashable verifies
+// that an equal source epoch with a different target epoch is not flagged
+// as surrounding or surrounded, matching the spec's strict-inequality
+// is_slashable_attestation_data condition.
+func TestStore_SurroundingSignedAttestation_EqualSourceNotSlashable(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "sql-slashing-protection")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+	defer store.Close()
+
+	var pubKey [48]byte
+	copy(pubKey[:], []byte("equal-source"))
+
+	if err := store.SaveSignedAttestation(ctx, pubKey, 10, 20); err != nil {
+		t.Fatal(err)
+	}
+
+	surrounding, err := store.SurroundingSignedAttestation(ctx, pubKey, 10, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if surrounding {
+		t.Fatal("equal source epoch with a different target must not be flagged as surrounding")
+	}
+
+	surrounded, err := store.SurroundingSignedAttestation(ctx, pubKey, 10, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if surrounded {
+		t.Fatal("equal source epoch with a different target must not be flagged as surrounded")
+	}
+}
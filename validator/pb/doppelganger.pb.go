@@ -0,0 +1,26 @@
+// This file is a hand-maintained placeholder for the message types declared
+// in doppelganger.proto: this tree has no protoc available, so the real
+// protoc-gen-go/protoc-gen-go-grpc output (proto.Message methods, wire
+// marshaling, the DoppelgangerService service's grpc.ServiceDesc and
+// client/server stubs) has not been generated here. Regenerating from
+// doppelganger.proto replaces this file.
+package pb
+
+// DoppelgangerStatusRequest lists the public keys to check the doppelganger
+// observation status of.
+type DoppelgangerStatusRequest struct {
+	PublicKeys [][]byte
+}
+
+// DoppelgangerStatusResponse reports, for every requested public key,
+// whether it has cleared its startup doppelganger observation window.
+type DoppelgangerStatusResponse struct {
+	Statuses []*DoppelgangerStatusResponse_Status
+}
+
+// DoppelgangerStatusResponse_Status is the per-key status entry of a
+// DoppelgangerStatusResponse.
+type DoppelgangerStatusResponse_Status struct {
+	PublicKey []byte
+	Ready     bool
+}
@@ -0,0 +1,31 @@
+// Package pb holds the generated protobuf types for the validator gRPC
+// service. This file is a hand-maintained placeholder for the message types
+// declared in slashing_protection.proto: this tree has no protoc available,
+// so the real protoc-gen-go/protoc-gen-go-grpc output (proto.Message
+// methods, wire marshaling, the SlashingProtection service's
+// grpc.ServiceDesc and client/server stubs) has not been generated here.
+// Regenerating from slashing_protection.proto replaces this file.
+package pb
+
+// ExportSlashingProtectionRequest requests the node's EIP-3076 slashing
+// protection interchange history.
+type ExportSlashingProtectionRequest struct {
+	Minimal bool
+}
+
+// ExportSlashingProtectionResponse carries the interchange history as a
+// JSON-encoded EIP-3076 file.
+type ExportSlashingProtectionResponse struct {
+	File []byte
+}
+
+// ImportSlashingProtectionRequest carries a JSON-encoded EIP-3076
+// interchange file to merge into the node's existing slashing protection
+// history.
+type ImportSlashingProtectionRequest struct {
+	File []byte
+}
+
+// ImportSlashingProtectionResponse is empty; a non-error response means the
+// import was merged successfully.
+type ImportSlashingProtectionResponse struct{}